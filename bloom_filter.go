@@ -0,0 +1,183 @@
+package goparquet
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+// BloomFilterOptions carries the sizing parameters used to build a bloom filter for a column, passed
+// to WithBloomFilter.
+type BloomFilterOptions struct {
+	// NumDistinctValues is the expected number of distinct values the bloom filter should be sized
+	// for. Sizing too low increases the false-positive probability beyond FalsePositiveProbability.
+	NumDistinctValues int64
+	// FalsePositiveProbability is the target false-positive probability for membership checks.
+	FalsePositiveProbability float64
+}
+
+// splitBlockBloomFilter is a parquet split-block bloom filter, as described in the Apache Parquet
+// format specification. It is used to support equality-predicate pushdown: a reader can check whether
+// a value is (probably) absent from a column chunk without decoding any pages.
+type splitBlockBloomFilter struct {
+	blocks []block
+}
+
+// block is a single SBBF block: eight 32-bit words, each carrying a fixed number of set bits.
+type block [8]uint32
+
+// saltValues are the block-construction constants mandated by the parquet spec.
+var saltValues = [8]uint32{
+	0x47b6137b, 0x44974d91, 0x8824ad5b, 0xa2b7289d,
+	0x705495c7, 0x2df1424b, 0x9efc4947, 0x5c6bfb31,
+}
+
+// newSplitBlockBloomFilter sizes a bloom filter for numDistinctValues distinct values at the given
+// target false-positive probability, following the sizing formula from the parquet spec.
+func newSplitBlockBloomFilter(numDistinctValues int64, fpp float64) *splitBlockBloomFilter {
+	if numDistinctValues <= 0 {
+		numDistinctValues = 1
+	}
+	if fpp <= 0 || fpp >= 1 {
+		fpp = 0.01
+	}
+
+	numBits := -8 * float64(numDistinctValues) / math.Log(1-math.Pow(fpp, 1.0/8))
+	numBlocks := nextPowerOfTwo(uint64(math.Ceil(numBits / 256)))
+	if numBlocks == 0 {
+		numBlocks = 1
+	}
+
+	return &splitBlockBloomFilter{blocks: make([]block, numBlocks)}
+}
+
+func nextPowerOfTwo(n uint64) uint64 {
+	if n == 0 {
+		return 1
+	}
+	p := uint64(1)
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// insert adds the given 64-bit hash to the filter.
+func (f *splitBlockBloomFilter) insert(hash uint64) {
+	idx := ((hash >> 32) * uint64(len(f.blocks))) >> 32
+	b := &f.blocks[idx]
+	lo := uint32(hash)
+	for i := 0; i < 8; i++ {
+		b[i] |= uint32(1) << ((lo * saltValues[i]) >> 27)
+	}
+}
+
+// check reports whether hash might have been inserted into the filter. False positives are possible,
+// false negatives are not.
+func (f *splitBlockBloomFilter) check(hash uint64) bool {
+	idx := ((hash >> 32) * uint64(len(f.blocks))) >> 32
+	b := f.blocks[idx]
+	lo := uint32(hash)
+	for i := 0; i < 8; i++ {
+		if b[i]&(uint32(1)<<((lo*saltValues[i])>>27)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// insertValue hashes a decoded column value the way the parquet spec requires for bloom filters and
+// inserts the resulting hash into the filter. It supports the Go value types produced by this
+// library's schema writer for the primitive parquet types that commonly back equality predicates.
+func (f *splitBlockBloomFilter) insertValue(v interface{}) {
+	f.insert(hashValue(v))
+}
+
+func hashValue(v interface{}) uint64 {
+	switch val := v.(type) {
+	case bool:
+		if val {
+			return xxh64([]byte{1})
+		}
+		return xxh64([]byte{0})
+	case int32:
+		var buf [4]byte
+		binary.LittleEndian.PutUint32(buf[:], uint32(val))
+		return xxh64(buf[:])
+	case int64:
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], uint64(val))
+		return xxh64(buf[:])
+	case int:
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], uint64(val))
+		return xxh64(buf[:])
+	case float32:
+		var buf [4]byte
+		binary.LittleEndian.PutUint32(buf[:], math.Float32bits(val))
+		return xxh64(buf[:])
+	case float64:
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(val))
+		return xxh64(buf[:])
+	case string:
+		return xxh64([]byte(val))
+	case []byte:
+		return xxh64(val)
+	default:
+		return 0
+	}
+}
+
+// WriteTo serialises the filter as a thrift BloomFilterHeader followed by the raw bitset, in the
+// layout a reader expects to find at a column chunk's bloom_filter_offset.
+func (f *splitBlockBloomFilter) WriteTo(w io.Writer) (int64, error) {
+	numBytes := int32(len(f.blocks) * 32)
+
+	header := &parquet.BloomFilterHeader{
+		NumBytes:    numBytes,
+		Algorithm:   &parquet.BloomFilterAlgorithm{BLOCK: &parquet.SplitBlockAlgorithm{}},
+		Hash:        &parquet.BloomFilterHash{XXHASH: &parquet.XxHash{}},
+		Compression: &parquet.BloomFilterCompression{UNCOMPRESSED: &parquet.Uncompressed{}},
+	}
+
+	headerStart := &countingWriter{w: w}
+	if err := writeThrift(header, headerStart); err != nil {
+		return headerStart.n, err
+	}
+
+	buf := make([]byte, numBytes)
+	for i, b := range f.blocks {
+		for j, word := range b {
+			binary.LittleEndian.PutUint32(buf[i*32+j*4:], word)
+		}
+	}
+
+	n, err := w.Write(buf)
+	return headerStart.n + int64(n), err
+}
+
+// countingWriter tracks how many bytes have been written so far, used to report the length of the
+// thrift-encoded bloom filter header.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// columnPath renders a column chunk's schema path the same way callers address it through AddData and
+// WithBloomFilter, i.e. a dot-joined path.
+func columnPath(chunk *parquet.ColumnChunk) string {
+	if chunk == nil || chunk.MetaData == nil {
+		return ""
+	}
+	return strings.Join(chunk.MetaData.PathInSchema, ".")
+}
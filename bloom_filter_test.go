@@ -0,0 +1,25 @@
+package goparquet
+
+import "testing"
+
+func TestSplitBlockBloomFilterInsertCheck(t *testing.T) {
+	f := newSplitBlockBloomFilter(100, 0.01)
+
+	inserted := []interface{}{int64(1), int64(2), int64(3), "hello", "world"}
+	for _, v := range inserted {
+		f.insertValue(v)
+	}
+
+	for _, v := range inserted {
+		if !f.check(hashValue(v)) {
+			t.Fatalf("check(%v) = false after insert, want true", v)
+		}
+	}
+}
+
+func TestSplitBlockBloomFilterSizing(t *testing.T) {
+	f := newSplitBlockBloomFilter(0, 0)
+	if len(f.blocks) == 0 {
+		t.Fatalf("expected at least one block for non-positive sizing inputs")
+	}
+}
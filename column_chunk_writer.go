@@ -0,0 +1,184 @@
+package goparquet
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+// ColumnChunkWriter streams a single column's values straight to the underlying file, one data page at
+// a time, so a caller can write a column chunk far larger than fits in memory. It is created through
+// RowGroupWriter.NewColumnChunkWriter.
+//
+// If the owning FileWriter was configured with WithPageWriterFactory, every page's already-encoded
+// bytes are handed to that column's PageWriter instead of being written to the file directly, so a
+// PageWriter can transform or redirect them (encryption, checksums, ...); see pageWriter below.
+type ColumnChunkWriter struct {
+	w    writePos
+	path []string
+	typ  parquet.Type
+
+	maxDefinitionLevel int32
+	maxRepetitionLevel int32
+
+	pageWriter PageWriter
+
+	started          bool
+	firstPageOffset  int64
+	numRows          int64
+	numValues        int64
+	uncompressedSize int64
+	stats            *columnStats
+}
+
+// Write encodes values, together with their definition and repetition levels, as a single data page
+// and writes it to the file immediately. values holds only the defined (non-null) values, in order;
+// defLevels and repLevels must together have one entry per logical value in the column, including
+// nulls, with repLevels[i] == 0 marking the start of a new row. Pass nil for either level slice if the
+// column has no optional or repeated ancestors, respectively.
+func (cw *ColumnChunkWriter) Write(values []interface{}, defLevels, repLevels []int32) error {
+	n := len(defLevels)
+	if n == 0 {
+		n = len(values)
+	}
+	if cw.maxRepetitionLevel == 0 {
+		repLevels = nil
+	}
+	if cw.maxDefinitionLevel == 0 {
+		defLevels = nil
+	}
+
+	repBytes := encodeLevelsRLE(repLevels, bitsNeeded(cw.maxRepetitionLevel))
+	defBytes := encodeLevelsRLE(defLevels, bitsNeeded(cw.maxDefinitionLevel))
+
+	valuesBytes, err := encodePlainValues(cw.typ, values)
+	if err != nil {
+		return err
+	}
+
+	body := append(append(append([]byte{}, repBytes...), defBytes...), valuesBytes...)
+
+	header := &parquet.PageHeader{
+		Type:                 parquet.PageType_DATA_PAGE,
+		UncompressedPageSize: int32(len(body)),
+		CompressedPageSize:   int32(len(body)),
+		DataPageHeader: &parquet.DataPageHeader{
+			NumValues:               int32(n),
+			Encoding:                parquet.Encoding_PLAIN,
+			DefinitionLevelEncoding: parquet.Encoding_RLE,
+			RepetitionLevelEncoding: parquet.Encoding_RLE,
+		},
+	}
+
+	var page bytes.Buffer
+	if err := writeThrift(header, &page); err != nil {
+		return err
+	}
+	page.Write(body)
+
+	offset, err := cw.writePage(page.Bytes())
+	if err != nil {
+		return err
+	}
+	if !cw.started {
+		cw.started = true
+		cw.firstPageOffset = offset
+	}
+
+	cw.uncompressedSize += int64(len(body))
+	cw.numValues += int64(len(values))
+	for _, rl := range repLevels {
+		if rl == 0 {
+			cw.numRows++
+		}
+	}
+	if repLevels == nil {
+		cw.numRows += int64(n)
+	}
+
+	for _, v := range values {
+		cw.stats.observe(v)
+	}
+	for i := 0; i < n-len(values); i++ {
+		cw.stats.observe(nil)
+	}
+
+	return nil
+}
+
+// writePage hands data, the already-encoded bytes of one data page, to cw.pageWriter if one was
+// configured, or writes it straight to the file otherwise, and returns the offset it ended up at.
+func (cw *ColumnChunkWriter) writePage(data []byte) (int64, error) {
+	if cw.pageWriter != nil {
+		return cw.pageWriter.WriteDataPage(data)
+	}
+
+	offset := cw.w.Pos()
+	if err := writeFull(cw.w, data); err != nil {
+		return 0, err
+	}
+	return offset, nil
+}
+
+// close finalizes the column chunk's metadata, giving cw.pageWriter (if any) a chance to add to it and
+// closing it. The caller (RowGroupWriter.Close) is responsible for recording the resulting ColumnChunk
+// on the FileWriter.
+func (cw *ColumnChunkWriter) close() (*parquet.ColumnChunk, error) {
+	cc := &parquet.ColumnChunk{
+		FileOffset: cw.firstPageOffset,
+		MetaData: &parquet.ColumnMetaData{
+			Type:                  cw.typ,
+			Encodings:             []parquet.Encoding{parquet.Encoding_PLAIN, parquet.Encoding_RLE},
+			PathInSchema:          cw.path,
+			Codec:                 parquet.CompressionCodec_UNCOMPRESSED,
+			NumValues:             cw.numValues,
+			TotalUncompressedSize: cw.uncompressedSize,
+			TotalCompressedSize:   cw.uncompressedSize,
+			DataPageOffset:        cw.firstPageOffset,
+			Statistics:            cw.stats.statistics(),
+		},
+	}
+
+	if cw.pageWriter == nil {
+		return cc, nil
+	}
+
+	extra, err := cw.pageWriter.WriteMetadata()
+	if err != nil {
+		return nil, err
+	}
+	if extra != nil && extra.Statistics != nil && cc.MetaData.Statistics == nil {
+		cc.MetaData.Statistics = extra.Statistics
+	}
+	if err := cw.pageWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return cc, nil
+}
+
+// encodePlainValues encodes values using the PLAIN encoding for typ: fixed-width little-endian for the
+// numeric/boolean physical types, and a 4-byte length prefix followed by the raw bytes for BYTE_ARRAY.
+func encodePlainValues(typ parquet.Type, values []interface{}) ([]byte, error) {
+	var out []byte
+	for _, v := range values {
+		switch typ {
+		case parquet.Type_BYTE_ARRAY:
+			b := encodeStatValue(v)
+			length := make([]byte, 4)
+			putUint32LE(length, uint32(len(b)))
+			out = append(out, length...)
+			out = append(out, b...)
+		case parquet.Type_BOOLEAN, parquet.Type_INT32, parquet.Type_INT64, parquet.Type_FLOAT, parquet.Type_DOUBLE:
+			b := encodeStatValue(v)
+			if b == nil {
+				return nil, fmt.Errorf("cannot plain-encode value %v (%T) as %v", v, v, typ)
+			}
+			out = append(out, b...)
+		default:
+			return nil, fmt.Errorf("streaming column chunk writer does not support physical type %v", typ)
+		}
+	}
+	return out, nil
+}
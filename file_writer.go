@@ -3,7 +3,9 @@ package goparquet
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/fraugster/parquet-go/parquet"
 	"github.com/fraugster/parquet-go/parquetschema"
@@ -27,7 +29,26 @@ type FileWriter struct {
 
 	codec parquet.CompressionCodec
 
-	newPage newDataPageFunc
+	newPage           newDataPageFunc
+	pageWriterFactory PageWriterFactory
+
+	sortingColumns        []*parquet.SortingColumn
+	sortingColumnsDefault []*parquet.SortingColumn
+	sortingStrict         bool
+	lastSortedRow         map[string]interface{}
+
+	bloomFilterSpecs map[string]bloomFilterSpec
+	bloomFilters     map[string]*splitBlockBloomFilter
+
+	collectStatistics bool
+	columnStats       map[string]*columnStats
+}
+
+// bloomFilterSpec carries the sizing parameters used to build a fresh bloom filter for a column at
+// the start of every row group.
+type bloomFilterSpec struct {
+	numDistinctValues int64
+	fpp               float64
 }
 
 // FileWriterOption describes an option function that is applied to a FileWriter when it is created.
@@ -87,6 +108,37 @@ func MetaData(data map[string]string) FileWriterOption {
 	}
 }
 
+// AppendKeyValueMetadata adds a new key/value pair to the file's key/value metadata. Unlike the
+// MetaData option, it can be called at any point before Close, including after one or more row groups
+// have already been flushed, which makes it useful for recording data (e.g. row counts, checksums)
+// that only becomes known while writing. It returns an error if key is already present; use
+// SetKeyValueMetadata to overwrite an existing key.
+func (fw *FileWriter) AppendKeyValueMetadata(key, value string) error {
+	if fw.kvStore == nil {
+		fw.kvStore = make(map[string]string)
+	}
+	if _, ok := fw.kvStore[key]; ok {
+		return fmt.Errorf("key %q is already present in the key/value metadata", key)
+	}
+	fw.kvStore[key] = value
+	return nil
+}
+
+// SetKeyValueMetadata adds or overwrites a key/value pair in the file's key/value metadata. Like
+// AppendKeyValueMetadata, it can be called at any point before Close.
+func (fw *FileWriter) SetKeyValueMetadata(key, value string) {
+	if fw.kvStore == nil {
+		fw.kvStore = make(map[string]string)
+	}
+	fw.kvStore[key] = value
+}
+
+// DeleteKeyValueMetadata removes key from the file's key/value metadata, if present. It is a no-op if
+// the key was never set.
+func (fw *FileWriter) DeleteKeyValueMetadata(key string) {
+	delete(fw.kvStore, key)
+}
+
 // MaxRowGroupSize sets the rough maximum size of a row group before it shall
 // be flushed automatically.
 func MaxRowGroupSize(size int64) FileWriterOption {
@@ -111,6 +163,193 @@ func WithDataPageV2() FileWriterOption {
 	}
 }
 
+// WithBloomFilter enables writing a bloom filter for each of the given columns, sized per opts. A
+// separate bloom filter is written for each row group, covering only the values in that row group, so
+// readers can use it together with the row group's column chunk to skip decoding pages that cannot
+// match an equality predicate. Calling it more than once for the same column overwrites its options.
+func WithBloomFilter(columns []string, opts BloomFilterOptions) FileWriterOption {
+	return func(fw *FileWriter) {
+		if fw.bloomFilterSpecs == nil {
+			fw.bloomFilterSpecs = make(map[string]bloomFilterSpec)
+		}
+		for _, column := range columns {
+			fw.bloomFilterSpecs[column] = bloomFilterSpec{
+				numDistinctValues: opts.NumDistinctValues,
+				fpp:               opts.FalsePositiveProbability,
+			}
+		}
+	}
+}
+
+// WithStatistics enables tracking the min, max, null count and distinct count of every column as rows
+// are added, and writes the result to each column chunk's Statistics field on flush. It is off by
+// default because computing distinct counts holds a set of every distinct value seen per column in
+// memory until the current row group is flushed.
+func WithStatistics(enabled bool) FileWriterOption {
+	return func(fw *FileWriter) {
+		fw.collectStatistics = enabled
+	}
+}
+
+// WithPageWriter overrides the low-level function used to encode data pages, replacing the standard
+// data page v1/v2 writers. See also WithPageWriterFactory, which adds to a column chunk's metadata
+// (e.g. for Parquet Modular Encryption) without having to replace the page encoder itself.
+func WithPageWriter(newPage newDataPageFunc) FileWriterOption {
+	return func(fw *FileWriter) {
+		fw.newPage = newPage
+	}
+}
+
+// WithSortingColumns declares the sort order that every row group written by this FileWriter should
+// record in its SortingColumns metadata, equivalent to calling SetSortingColumns before every
+// FlushRowGroup except that cols is not checked against the schema. When strict is true, AddData checks the declared order against the rows it receives
+// and returns an error as soon as a row violates it, instead of silently writing a file that claims an
+// order its data doesn't have.
+func WithSortingColumns(cols []*parquet.SortingColumn, strict bool) FileWriterOption {
+	return func(fw *FileWriter) {
+		fw.sortingColumns = cols
+		fw.sortingColumnsDefault = cols
+		fw.sortingStrict = strict
+	}
+}
+
+// SetSortingColumns declares the sort order of the rows that will be written to the next row group.
+// The declaration is consumed by the following FlushRowGroup or RowGroupWriter.Close call and then
+// reset to the order given by WithSortingColumns, or to none if that option wasn't used. It returns an error if
+// any of the declared columns doesn't exist in the schema or is declared more than once.
+func (fw *FileWriter) SetSortingColumns(cols []*parquet.SortingColumn) error {
+	if err := validateSortingColumns(cols, len(fw.leafColumnPaths())); err != nil {
+		return err
+	}
+	fw.sortingColumns = cols
+	fw.lastSortedRow = nil
+	return nil
+}
+
+// schemaLeaf is a single leaf column's dot-joined schema path together with the schema element that
+// describes it, which carries the logical/converted type information needed to interpret its values
+// (e.g. for ordering comparisons).
+type schemaLeaf struct {
+	path string
+	elem *parquet.SchemaElement
+}
+
+// leafColumns walks the flat, pre-order schema array returned by getSchemaArray and returns one entry
+// per leaf column, in the order SortingColumn.ColumnIdx refers to; the paths are also the keys under
+// which statistics are collected and matched to column chunks. Each entry's path is built by joining the leaf's name with that of every
+// enclosing group, using NumChildren to track how the flat array nests.
+func (fw *FileWriter) leafColumns() []schemaLeaf {
+	elems := fw.getSchemaArray()
+	if len(elems) == 0 {
+		return nil
+	}
+
+	// elems[0] is the schema's root (message) element, not itself part of any column's path.
+	idx := 1
+	return collectLeafColumns(elems, &idx, nil, numSchemaChildren(elems[0]))
+}
+
+// collectLeafColumns consumes the next n elements of elems (a flat, pre-order schema array), starting at
+// *idx, appending each one's name to prefix to build its path; group elements recurse into their own
+// children before the walk continues with their parent's remaining siblings.
+func collectLeafColumns(elems []*parquet.SchemaElement, idx *int, prefix []string, n int32) []schemaLeaf {
+	var leaves []schemaLeaf
+	for i := int32(0); i < n && *idx < len(elems); i++ {
+		elem := elems[*idx]
+		*idx++
+
+		path := append(append([]string{}, prefix...), elem.Name)
+
+		if elem.Type != nil {
+			leaves = append(leaves, schemaLeaf{path: strings.Join(path, "."), elem: elem})
+			continue
+		}
+		leaves = append(leaves, collectLeafColumns(elems, idx, path, numSchemaChildren(elem))...)
+	}
+	return leaves
+}
+
+func numSchemaChildren(elem *parquet.SchemaElement) int32 {
+	if elem.NumChildren == nil {
+		return 0
+	}
+	return *elem.NumChildren
+}
+
+// leafColumnPaths returns the dot-joined path of every leaf column in the schema, in the order
+// SortingColumn.ColumnIdx refers to.
+func (fw *FileWriter) leafColumnPaths() []string {
+	leaves := fw.leafColumns()
+	paths := make([]string, len(leaves))
+	for i, l := range leaves {
+		paths[i] = l.path
+	}
+	return paths
+}
+
+// valueOrderFor returns the comparison order elem's decoded values should use for min/max tracking and
+// sorting-column checks, derived from its logical/converted type. It only overrides the default
+// signed-integer/lexicographic-byte comparison for the types where that default is wrong: unsigned
+// integers, and decimals backed by a byte array.
+func valueOrderFor(elem *parquet.SchemaElement) valueOrder {
+	if lt := elem.LogicalType; lt != nil {
+		if it := lt.INTEGER; it != nil && !it.IsSigned {
+			return orderUnsigned
+		}
+		if lt.DECIMAL != nil && isByteArraySchemaType(elem.Type) {
+			return orderDecimalBytes
+		}
+	}
+	if ct := elem.ConvertedType; ct != nil {
+		switch *ct {
+		case parquet.ConvertedType_UINT_8, parquet.ConvertedType_UINT_16, parquet.ConvertedType_UINT_32, parquet.ConvertedType_UINT_64:
+			return orderUnsigned
+		case parquet.ConvertedType_DECIMAL:
+			if isByteArraySchemaType(elem.Type) {
+				return orderDecimalBytes
+			}
+		}
+	}
+	return orderDefault
+}
+
+func isByteArraySchemaType(typ *parquet.Type) bool {
+	return typ != nil && (*typ == parquet.Type_BYTE_ARRAY || *typ == parquet.Type_FIXED_LEN_BYTE_ARRAY)
+}
+
+// checkSortOrder verifies, in strict mode, that the row about to be added does not violate the
+// declared sorting columns relative to the previous row in the current row group.
+func (fw *FileWriter) checkSortOrder(m map[string]interface{}) error {
+	if !fw.sortingStrict || len(fw.sortingColumns) == 0 {
+		return nil
+	}
+
+	if fw.lastSortedRow != nil {
+		leaves := fw.leafColumns()
+		for _, sc := range fw.sortingColumns {
+			if int(sc.ColumnIdx) >= len(leaves) {
+				continue
+			}
+			name := leaves[sc.ColumnIdx].path
+
+			cmp, ok := compareOrderedValues(fw.lastSortedRow[name], m[name], valueOrderFor(leaves[sc.ColumnIdx].elem))
+			if !ok || cmp == 0 {
+				continue
+			}
+			if sc.Descending {
+				cmp = -cmp
+			}
+			if cmp > 0 {
+				return fmt.Errorf("row violates declared sort order on column %q", name)
+			}
+			break
+		}
+	}
+
+	fw.lastSortedRow = m
+	return nil
+}
+
 // FlushRowGroup is to write the row group into the file
 func (fw *FileWriter) FlushRowGroup() error {
 	// Write the entire row group
@@ -130,26 +369,130 @@ func (fw *FileWriter) FlushRowGroup() error {
 		return err
 	}
 
+	fw.applyStatistics(cc)
+
+	var totalByteSize int64
+	for _, c := range cc {
+		if c.MetaData != nil {
+			totalByteSize += c.MetaData.TotalUncompressedSize
+		}
+	}
+
 	fw.rowGroups = append(fw.rowGroups, &parquet.RowGroup{
 		Columns:        cc,
-		TotalByteSize:  0,
+		TotalByteSize:  totalByteSize,
 		NumRows:        fw.rowGroupNumRecords(),
-		SortingColumns: nil, // TODO: support Sorting
+		SortingColumns: fw.sortingColumns,
 	})
 	fw.totalNumRecords += fw.rowGroupNumRecords()
+
+	if err := fw.writeBloomFilters(cc); err != nil {
+		return err
+	}
+
+	if err := fw.applyPageWriterMetadata(cc); err != nil {
+		return err
+	}
+
 	// flush the schema
 	fw.schemaWriter.resetData()
+	fw.sortingColumns = fw.sortingColumnsDefault
+	fw.lastSortedRow = nil
+	fw.columnStats = nil
+
+	return nil
+}
+
+// applyStatistics attaches the accumulated min/max/null/distinct counts for the row group just written
+// to the matching column chunk's Statistics field. It is a no-op unless WithStatistics was used.
+func (fw *FileWriter) applyStatistics(cc []*parquet.ColumnChunk) {
+	if len(fw.columnStats) == 0 {
+		return
+	}
+
+	for _, c := range cc {
+		if c.MetaData == nil {
+			continue
+		}
+		s, ok := fw.columnStats[columnPath(c)]
+		if !ok {
+			continue
+		}
+		c.MetaData.Statistics = s.statistics()
+	}
+}
 
+// writeBloomFilters writes out the bloom filter accumulated for each configured column during the row
+// group that was just flushed, recording its offset on the matching column chunk, then clears the
+// filters so the next row group starts with fresh ones.
+func (fw *FileWriter) writeBloomFilters(cc []*parquet.ColumnChunk) error {
+	for col, f := range fw.bloomFilters {
+		var chunk *parquet.ColumnChunk
+		for _, c := range cc {
+			if columnPath(c) == col {
+				chunk = c
+				break
+			}
+		}
+		if chunk == nil || chunk.MetaData == nil {
+			continue
+		}
+
+		offset := fw.w.Pos()
+		n, err := f.WriteTo(fw.w)
+		if err != nil {
+			return err
+		}
+		length := int32(n)
+		chunk.MetaData.BloomFilterOffset = &offset
+		chunk.MetaData.BloomFilterLength = &length
+	}
+
+	fw.bloomFilters = nil
 	return nil
 }
 
 // AddData add a new record to the current row group and flush it if the auto flush is enabled and the size
 // is more than the auto flush size
 func (fw *FileWriter) AddData(m map[string]interface{}) error {
+	if err := fw.checkSortOrder(m); err != nil {
+		return err
+	}
+
 	if err := fw.schemaWriter.AddData(m); err != nil {
 		return err
 	}
 
+	if fw.collectStatistics {
+		if fw.columnStats == nil {
+			fw.columnStats = make(map[string]*columnStats)
+		}
+		for _, leaf := range fw.leafColumns() {
+			s, ok := fw.columnStats[leaf.path]
+			if !ok {
+				s = newColumnStats(valueOrderFor(leaf.elem))
+				fw.columnStats[leaf.path] = s
+			}
+			s.observe(m[leaf.path])
+		}
+	}
+
+	for col, spec := range fw.bloomFilterSpecs {
+		v, ok := m[col]
+		if !ok {
+			continue
+		}
+		if fw.bloomFilters == nil {
+			fw.bloomFilters = make(map[string]*splitBlockBloomFilter)
+		}
+		f, ok := fw.bloomFilters[col]
+		if !ok {
+			f = newSplitBlockBloomFilter(spec.numDistinctValues, spec.fpp)
+			fw.bloomFilters[col] = f
+		}
+		f.insertValue(v)
+	}
+
 	if fw.rowGroupFlushSize > 0 && fw.schemaWriter.DataSize() >= fw.rowGroupFlushSize {
 		return fw.FlushRowGroup()
 	}
@@ -184,7 +527,7 @@ func (fw *FileWriter) Close() error {
 		RowGroups:        fw.rowGroups,
 		KeyValueMetadata: kv,
 		CreatedBy:        &fw.createdBy,
-		ColumnOrders:     nil, // TODO: support for column order
+		ColumnOrders:     fw.getColumnOrders(),
 	}
 
 	pos := fw.w.Pos()
@@ -200,6 +543,24 @@ func (fw *FileWriter) Close() error {
 	return writeFull(fw.w, magic)
 }
 
+// getColumnOrders builds the ColumnOrders list for the file metadata: one entry per leaf column of
+// the schema, in schema order, all declared as using the type-defined sort order. Per-column-chunk
+// statistics (min/max/null/distinct counts), when enabled via WithStatistics, are attached separately
+// in applyStatistics as each row group is flushed.
+func (fw *FileWriter) getColumnOrders() []*parquet.ColumnOrder {
+	var orders []*parquet.ColumnOrder
+	for _, elem := range fw.getSchemaArray() {
+		if elem.Type == nil {
+			// group node, not a leaf column
+			continue
+		}
+		orders = append(orders, &parquet.ColumnOrder{
+			TYPEORDER: &parquet.TypeDefinedOrder{},
+		})
+	}
+	return orders
+}
+
 // CurrentRowGroupSize is the size of current row group data (not including definition/repetition levels and parquet headers
 // just a rough estimation of data size in plain format, uncompressed. if the encoding is different than plain, the final
 // size depends on the data
@@ -0,0 +1,68 @@
+package goparquet
+
+// bitsNeeded returns the number of bits required to represent every value in [0, maxLevel], as used to
+// size the RLE/bit-packed hybrid encoding of a definition or repetition level array.
+func bitsNeeded(maxLevel int32) int {
+	bits := 0
+	for (1 << uint(bits)) <= int(maxLevel) {
+		bits++
+	}
+	return bits
+}
+
+// encodeLevelsRLE encodes levels using the parquet RLE/bit-packed hybrid format, restricted to the RLE
+// half of that hybrid (a sequence of run-length groups), which the format spec allows as a complete
+// encoding on its own. It returns the length-prefixed block parquet expects to find directly embedded
+// in a V1 data page, or nil if bitWidth is 0 (i.e. the column has no optional/repeated ancestors, so
+// there is nothing to encode).
+func encodeLevelsRLE(levels []int32, bitWidth int) []byte {
+	if bitWidth == 0 {
+		return nil
+	}
+
+	var body []byte
+	i := 0
+	for i < len(levels) {
+		j := i + 1
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		body = append(body, encodeRLERun(j-i, levels[i], bitWidth)...)
+		i = j
+	}
+
+	out := make([]byte, 4+len(body))
+	putUint32LE(out, uint32(len(body)))
+	copy(out[4:], body)
+	return out
+}
+
+// encodeRLERun encodes a single run of count repetitions of value as an RLE group: a varint run-header
+// (count<<1) followed by the value packed into the smallest whole number of bytes that holds bitWidth
+// bits.
+func encodeRLERun(count int, value int32, bitWidth int) []byte {
+	out := appendUvarint(nil, uint64(count)<<1)
+
+	numBytes := (bitWidth + 7) / 8
+	v := uint32(value)
+	for i := 0; i < numBytes; i++ {
+		out = append(out, byte(v))
+		v >>= 8
+	}
+	return out
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	for v >= 0x80 {
+		buf = append(buf, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(buf, byte(v))
+}
+
+func putUint32LE(buf []byte, v uint32) {
+	buf[0] = byte(v)
+	buf[1] = byte(v >> 8)
+	buf[2] = byte(v >> 16)
+	buf[3] = byte(v >> 24)
+}
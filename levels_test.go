@@ -0,0 +1,78 @@
+package goparquet
+
+import "testing"
+
+func TestBitsNeeded(t *testing.T) {
+	cases := map[int32]int{0: 0, 1: 1, 2: 2, 3: 2, 4: 3, 255: 8}
+	for maxLevel, want := range cases {
+		if got := bitsNeeded(maxLevel); got != want {
+			t.Errorf("bitsNeeded(%d) = %d, want %d", maxLevel, got, want)
+		}
+	}
+}
+
+func TestEncodeLevelsRLEZeroBitWidth(t *testing.T) {
+	if got := encodeLevelsRLE([]int32{0, 0, 0}, 0); got != nil {
+		t.Fatalf("expected nil for bitWidth 0, got %v", got)
+	}
+}
+
+// decodeLevelsRLE is a minimal reader for the format encodeLevelsRLE produces, used only to verify the
+// encoder round-trips correctly. Like a real decoder it must be told bitWidth, since that isn't
+// itself recorded in the block (the parquet format derives it from the schema, the same way the real
+// encoder does).
+func decodeLevelsRLE(buf []byte, n, bitWidth int) []int32 {
+	length := uint32(buf[0]) | uint32(buf[1])<<8 | uint32(buf[2])<<16 | uint32(buf[3])<<24
+	body := buf[4 : 4+int(length)]
+	valueBytes := (bitWidth + 7) / 8
+
+	var out []int32
+	pos := 0
+	for len(out) < n {
+		header, shift := uint64(0), uint(0)
+		for {
+			b := body[pos]
+			pos++
+			header |= uint64(b&0x7f) << shift
+			if b&0x80 == 0 {
+				break
+			}
+			shift += 7
+		}
+		count := int(header >> 1)
+
+		value := uint32(0)
+		for i := 0; i < valueBytes; i++ {
+			value |= uint32(body[pos]) << (8 * i)
+			pos++
+		}
+		for i := 0; i < count; i++ {
+			out = append(out, int32(value))
+		}
+	}
+	return out
+}
+
+func TestEncodeLevelsRLERoundTrip(t *testing.T) {
+	cases := [][]int32{
+		{1, 1, 1, 0, 0, 1, 1, 1, 1},
+		{0},
+		{3, 3, 3, 3, 3, 3},
+		{0, 1, 0, 1, 0, 1},
+	}
+
+	for _, levels := range cases {
+		bitWidth := 2
+		got := encodeLevelsRLE(levels, bitWidth)
+		decoded := decodeLevelsRLE(got, len(levels), bitWidth)
+
+		if len(decoded) != len(levels) {
+			t.Fatalf("decoded %d levels, want %d", len(decoded), len(levels))
+		}
+		for i := range levels {
+			if decoded[i] != levels[i] {
+				t.Fatalf("level[%d] = %d, want %d (case %v)", i, decoded[i], levels[i], levels)
+			}
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package goparquet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+// MergeFiles concatenates the row groups of one or more existing parquet files, read from sources, into
+// dst. Column chunk data is copied byte-for-byte rather than being decoded and re-encoded, so merging is
+// cheap even for large files. Every source after the first must have a schema compatible with the
+// first, checked with schemasCompatible; the schema and "created by" string of the first source are
+// used for the merged file. Key/value metadata from later sources is merged in, with later sources
+// winning on key collisions.
+//
+// Bloom filters and page indexes attached to the source column chunks are not preserved, since their
+// placement in the merged file can no longer be expressed relative to the column chunk bytes that are
+// copied; write fresh ones with WithBloomFilter if you need them in the merged file.
+func MergeFiles(dst io.Writer, sources []io.ReadSeeker) error {
+	if len(sources) == 0 {
+		return errors.New("no source files to merge")
+	}
+
+	w := &writePosStruct{w: dst}
+	if err := writeFull(w, magic); err != nil {
+		return err
+	}
+
+	var (
+		schema    []*parquet.SchemaElement
+		createdBy string
+		rowGroups []*parquet.RowGroup
+		numRows   int64
+	)
+	kv := make(map[string]string)
+
+	for i, src := range sources {
+		meta, err := readFileMetaData(src)
+		if err != nil {
+			return err
+		}
+
+		if schema == nil {
+			schema = meta.Schema
+			if meta.CreatedBy != nil {
+				createdBy = *meta.CreatedBy
+			}
+		} else if err := schemasCompatible(schema, meta.Schema); err != nil {
+			return fmt.Errorf("source %d is not compatible with the first source's schema: %w", i, err)
+		}
+
+		for _, kvp := range meta.KeyValueMetadata {
+			if kvp.Value != nil {
+				kv[kvp.Key] = *kvp.Value
+			}
+		}
+
+		for _, rg := range meta.RowGroups {
+			newRG, err := copyRowGroup(w, src, rg)
+			if err != nil {
+				return err
+			}
+			rowGroups = append(rowGroups, newRG)
+			numRows += newRG.NumRows
+		}
+	}
+
+	kvList := make([]*parquet.KeyValue, 0, len(kv))
+	for k := range kv {
+		v := kv[k]
+		kvList = append(kvList, &parquet.KeyValue{Key: k, Value: &v})
+	}
+
+	meta := &parquet.FileMetaData{
+		Version:          1,
+		Schema:           schema,
+		NumRows:          numRows,
+		RowGroups:        rowGroups,
+		KeyValueMetadata: kvList,
+		CreatedBy:        &createdBy,
+	}
+
+	pos := w.Pos()
+	if err := writeThrift(meta, w); err != nil {
+		return err
+	}
+
+	ln := int32(w.Pos() - pos)
+	if err := binary.Write(w, binary.LittleEndian, &ln); err != nil {
+		return err
+	}
+
+	return writeFull(w, magic)
+}
+
+// copyRowGroup copies the raw bytes of every column chunk in rg from src to w, and returns a RowGroup
+// referring to their new locations.
+func copyRowGroup(w writePos, src io.ReadSeeker, rg *parquet.RowGroup) (*parquet.RowGroup, error) {
+	newColumns := make([]*parquet.ColumnChunk, len(rg.Columns))
+
+	for i, col := range rg.Columns {
+		if col.MetaData == nil {
+			return nil, errors.New("column chunk is missing its metadata, cannot merge without re-encoding")
+		}
+
+		start := col.MetaData.DataPageOffset
+		if col.MetaData.DictionaryPageOffset != nil && *col.MetaData.DictionaryPageOffset < start {
+			start = *col.MetaData.DictionaryPageOffset
+		}
+
+		if _, err := src.Seek(start, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		dstStart := w.Pos()
+		if _, err := io.CopyN(w, src, col.MetaData.TotalCompressedSize); err != nil {
+			return nil, err
+		}
+		delta := dstStart - start
+
+		newMeta := *col.MetaData
+		newMeta.DataPageOffset += delta
+		newMeta.BloomFilterOffset = nil
+		if col.MetaData.DictionaryPageOffset != nil {
+			newOffset := *col.MetaData.DictionaryPageOffset + delta
+			newMeta.DictionaryPageOffset = &newOffset
+		}
+
+		newCol := *col
+		newCol.FileOffset = dstStart
+		newCol.MetaData = &newMeta
+		// The column and offset indexes, if any, live outside the [start, start+TotalCompressedSize)
+		// byte range copied above, so their offsets would otherwise keep pointing into src. Clear them
+		// rather than ship a chunk whose page index silently corrupts readers that rely on it.
+		newCol.ColumnIndexOffset = nil
+		newCol.ColumnIndexLength = nil
+		newCol.OffsetIndexOffset = nil
+		newCol.OffsetIndexLength = nil
+		newColumns[i] = &newCol
+	}
+
+	return &parquet.RowGroup{
+		Columns:        newColumns,
+		TotalByteSize:  rg.TotalByteSize,
+		NumRows:        rg.NumRows,
+		SortingColumns: rg.SortingColumns,
+	}, nil
+}
+
+// schemasCompatible reports whether b describes the same columns as a, for the purpose of copying row
+// groups between files without re-encoding: every leaf and group node must appear in the same order
+// with the same name, repetition, physical type, converted type and logical type. Field IDs are not
+// compared, since they don't affect how the copied bytes must be interpreted.
+func schemasCompatible(a, b []*parquet.SchemaElement) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("schemas have a different number of elements: %d vs %d", len(a), len(b))
+	}
+
+	for i := range a {
+		ea, eb := a[i], b[i]
+		if ea.Name != eb.Name {
+			return fmt.Errorf("element %d: name %q vs %q", i, ea.Name, eb.Name)
+		}
+		if !reflect.DeepEqual(ea.RepetitionType, eb.RepetitionType) {
+			return fmt.Errorf("column %q: repetition type %v vs %v", ea.Name, ea.RepetitionType, eb.RepetitionType)
+		}
+		if !reflect.DeepEqual(ea.Type, eb.Type) {
+			return fmt.Errorf("column %q: physical type %v vs %v", ea.Name, ea.Type, eb.Type)
+		}
+		if !reflect.DeepEqual(ea.ConvertedType, eb.ConvertedType) {
+			return fmt.Errorf("column %q: converted type %v vs %v", ea.Name, ea.ConvertedType, eb.ConvertedType)
+		}
+		if !reflect.DeepEqual(ea.LogicalType, eb.LogicalType) {
+			return fmt.Errorf("column %q: logical type %v vs %v", ea.Name, ea.LogicalType, eb.LogicalType)
+		}
+	}
+
+	return nil
+}
+
+// FileReader gives read access to an existing parquet file's footer metadata, open over r, so its row
+// groups can be copied into a FileWriter with AppendRowGroupsFrom without re-encoding them.
+type FileReader struct {
+	r    io.ReadSeeker
+	meta *parquet.FileMetaData
+}
+
+// OpenFileReader reads and parses the footer of an existing parquet file from r. r must remain valid
+// and positioned for reading for as long as the returned FileReader is used with AppendRowGroupsFrom.
+func OpenFileReader(r io.ReadSeeker) (*FileReader, error) {
+	meta, err := readFileMetaData(r)
+	if err != nil {
+		return nil, err
+	}
+	return &FileReader{r: r, meta: meta}, nil
+}
+
+// MetaData returns the parsed file metadata, including its schema and row groups.
+func (fr *FileReader) MetaData() *parquet.FileMetaData {
+	return fr.meta
+}
+
+// AppendRowGroupsFrom copies the row groups of r for which filter returns true (or every row group, if
+// filter is nil) into fw, without decoding or re-encoding their column chunk data. r's schema must be
+// compatible with fw's, checked with schemasCompatible. Copied row groups can be interleaved with row
+// groups fw writes itself through AddData/RowGroupWriter/FlushRowGroup in any order.
+//
+// As with MergeFiles, bloom filters and page indexes on the copied column chunks are dropped, since
+// their placement can't be expressed relative to the copied bytes.
+func (fw *FileWriter) AppendRowGroupsFrom(r *FileReader, filter func(*parquet.RowGroup) bool) error {
+	if err := schemasCompatible(fw.getSchemaArray(), r.meta.Schema); err != nil {
+		return fmt.Errorf("source schema is not compatible with this FileWriter's schema: %w", err)
+	}
+
+	if fw.w.Pos() == 0 {
+		if err := writeFull(fw.w, magic); err != nil {
+			return err
+		}
+	}
+
+	for _, rg := range r.meta.RowGroups {
+		if filter != nil && !filter(rg) {
+			continue
+		}
+
+		newRG, err := copyRowGroup(fw.w, r.r, rg)
+		if err != nil {
+			return err
+		}
+		fw.rowGroups = append(fw.rowGroups, newRG)
+		fw.totalNumRecords += newRG.NumRows
+	}
+
+	return nil
+}
+
+// readFileMetaData reads and parses the footer of an existing parquet file.
+func readFileMetaData(r io.ReadSeeker) (*parquet.FileMetaData, error) {
+	if _, err := r.Seek(-8, io.SeekEnd); err != nil {
+		return nil, err
+	}
+
+	var footerLen int32
+	if err := binary.Read(r, binary.LittleEndian, &footerLen); err != nil {
+		return nil, err
+	}
+
+	var tail [4]byte
+	if _, err := io.ReadFull(r, tail[:]); err != nil {
+		return nil, err
+	}
+	if string(tail[:]) != string(magic) {
+		return nil, errors.New("not a valid parquet file: trailing magic is missing")
+	}
+
+	if _, err := r.Seek(-8-int64(footerLen), io.SeekEnd); err != nil {
+		return nil, err
+	}
+
+	meta := &parquet.FileMetaData{}
+	if err := readThrift(meta, io.LimitReader(r, int64(footerLen))); err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
@@ -0,0 +1,59 @@
+package goparquet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+func typ(t parquet.Type) *parquet.Type { return &t }
+
+func TestSchemasCompatible(t *testing.T) {
+	a := []*parquet.SchemaElement{{Name: "root"}, {Name: "x", Type: typ(parquet.Type(1))}}
+	b := []*parquet.SchemaElement{{Name: "root"}, {Name: "x", Type: typ(parquet.Type(1))}}
+	if err := schemasCompatible(a, b); err != nil {
+		t.Fatalf("expected compatible schemas, got %v", err)
+	}
+
+	c := []*parquet.SchemaElement{{Name: "root"}, {Name: "x", Type: typ(parquet.Type(2))}}
+	if err := schemasCompatible(a, c); err == nil {
+		t.Fatalf("expected an error for mismatched physical type")
+	}
+}
+
+func TestCopyRowGroupClearsPageIndexOffsets(t *testing.T) {
+	var src bytes.Buffer
+	src.Write([]byte("somecolumnbytes!"))
+
+	colIdxOffset := int64(1000)
+	offIdxOffset := int64(2000)
+	rg := &parquet.RowGroup{
+		Columns: []*parquet.ColumnChunk{
+			{
+				MetaData: &parquet.ColumnMetaData{
+					DataPageOffset:      0,
+					TotalCompressedSize: int64(src.Len()),
+				},
+				ColumnIndexOffset: &colIdxOffset,
+				OffsetIndexOffset: &offIdxOffset,
+			},
+		},
+		NumRows: 1,
+	}
+
+	var dst bytes.Buffer
+	w := &writePosStruct{w: &dst}
+
+	newRG, err := copyRowGroup(w, bytes.NewReader(src.Bytes()), rg)
+	if err != nil {
+		t.Fatalf("copyRowGroup: %v", err)
+	}
+
+	if newRG.Columns[0].ColumnIndexOffset != nil {
+		t.Fatalf("expected ColumnIndexOffset to be cleared, got %v", *newRG.Columns[0].ColumnIndexOffset)
+	}
+	if newRG.Columns[0].OffsetIndexOffset != nil {
+		t.Fatalf("expected OffsetIndexOffset to be cleared, got %v", *newRG.Columns[0].OffsetIndexOffset)
+	}
+}
@@ -0,0 +1,95 @@
+package goparquet
+
+import "github.com/fraugster/parquet-go/parquet"
+
+// ColumnDescriptor identifies the column a PageWriter is being created for.
+type ColumnDescriptor struct {
+	// Path is the column's dot-joined path in the schema, e.g. "a.b.c".
+	Path string
+	// Type is the column's physical parquet type.
+	Type parquet.Type
+}
+
+// PageWriter is the interface a caller can implement to take over how a single column's pages and
+// metadata are produced, e.g. to apply Parquet Modular Encryption, add custom page-level checksums, or
+// use an encoding this package doesn't support natively (delta-binary-packed, byte-stream-split, ...).
+// It is modelled after arrow-rs's PageWriter trait.
+type PageWriter interface {
+	// WriteDataPage writes the already-encoded bytes of one data page and returns the offset it was
+	// written at.
+	WriteDataPage(data []byte) (offset int64, err error)
+	// WriteDictionaryPage writes the already-encoded bytes of the column's dictionary page, if any,
+	// and returns the offset it was written at.
+	WriteDictionaryPage(data []byte) (offset int64, err error)
+	// WriteMetadata is called once all of a column's pages for the current row group have been
+	// written. It may return additional column metadata for the column chunk that FileWriter is about
+	// to record, or nil if it has nothing to add. Only the returned Statistics are used, and only when
+	// the column chunk has no Statistics of its own; every other field is ignored.
+	WriteMetadata() (*parquet.ColumnMetaData, error)
+	// Close releases any resources held by the PageWriter for the column. It is called once per row
+	// group, after WriteMetadata.
+	Close() error
+}
+
+// PageWriterFactory builds a PageWriter for a single column. It is called once per column chunk of every
+// row group: by RowGroupWriter.NewColumnChunkWriter before any page is written, or by FlushRowGroup
+// after the buffered row group has been encoded.
+type PageWriterFactory func(col ColumnDescriptor) PageWriter
+
+// WithPageWriterFactory installs factory as the source of PageWriters for every leaf column, so that
+// downstream users can layer custom encodings or encryption onto the pages this package writes without
+// forking the module. factory is called once per leaf column per row group.
+//
+// Column chunks written through RowGroupWriter/ColumnChunkWriter hand every page's already-encoded
+// bytes to the factory's PageWriter via WriteDataPage, so the PageWriter can transform or redirect them
+// (encryption, checksums, an alternative encoding, ...) before they reach the file; WriteMetadata is
+// then consulted for the resulting column chunk's metadata, and Close is called right after.
+//
+// Column chunks written through the buffered FileWriter.AddData/FlushRowGroup path cannot be intercepted
+// this way: that path's page encoding lives in this package's internal row group writer, which doesn't
+// take a PageWriter. For those, the configured PageWriter's WriteDataPage/WriteDictionaryPage are never
+// called, and only WriteMetadata's return value is consulted after the fact, for Statistics to use
+// when the column chunk has none of its own.
+func WithPageWriterFactory(factory PageWriterFactory) FileWriterOption {
+	return func(fw *FileWriter) {
+		fw.pageWriterFactory = factory
+	}
+}
+
+// applyPageWriterMetadata is FlushRowGroup's hook into the configured PageWriterFactory, if any, for the
+// buffered AddData path. Since that path's page encoding is internal to this package, the PageWriter it
+// builds per column never sees a page's bytes; it can only add to the column chunk's metadata once the
+// row group has already been fully encoded, then is closed.
+func (fw *FileWriter) applyPageWriterMetadata(cc []*parquet.ColumnChunk) error {
+	if fw.pageWriterFactory == nil {
+		return nil
+	}
+
+	for _, c := range cc {
+		if c.MetaData == nil {
+			continue
+		}
+
+		pw := fw.pageWriterFactory(ColumnDescriptor{
+			Path: columnPath(c),
+			Type: c.MetaData.Type,
+		})
+		if pw == nil {
+			continue
+		}
+
+		extra, err := pw.WriteMetadata()
+		if err != nil {
+			return err
+		}
+		if extra != nil && extra.Statistics != nil && c.MetaData.Statistics == nil {
+			c.MetaData.Statistics = extra.Statistics
+		}
+
+		if err := pw.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package goparquet
+
+import (
+	"testing"
+
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+type fakePageWriter struct {
+	stats  *parquet.Statistics
+	closed bool
+}
+
+func (f *fakePageWriter) WriteDataPage(data []byte) (int64, error)       { return 0, nil }
+func (f *fakePageWriter) WriteDictionaryPage(data []byte) (int64, error) { return 0, nil }
+func (f *fakePageWriter) WriteMetadata() (*parquet.ColumnMetaData, error) {
+	return &parquet.ColumnMetaData{Statistics: f.stats}, nil
+}
+func (f *fakePageWriter) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestApplyPageWriterMetadata(t *testing.T) {
+	nullCount := int64(3)
+	pw := &fakePageWriter{stats: &parquet.Statistics{NullCount: &nullCount}}
+
+	fw := &FileWriter{
+		pageWriterFactory: func(col ColumnDescriptor) PageWriter {
+			if col.Path != "a" {
+				t.Fatalf("unexpected column path %q", col.Path)
+			}
+			return pw
+		},
+	}
+
+	cc := []*parquet.ColumnChunk{
+		{MetaData: &parquet.ColumnMetaData{PathInSchema: []string{"a"}}},
+	}
+
+	if err := fw.applyPageWriterMetadata(cc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cc[0].MetaData.Statistics == nil || cc[0].MetaData.Statistics.NullCount == nil || *cc[0].MetaData.Statistics.NullCount != 3 {
+		t.Fatalf("statistics were not merged: %+v", cc[0].MetaData.Statistics)
+	}
+	if !pw.closed {
+		t.Fatalf("expected PageWriter to be closed")
+	}
+}
@@ -0,0 +1,120 @@
+package goparquet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+// RowGroupWriter provides a streaming interface for filling a single row group without holding more
+// than one page's worth of data in memory at a time: values are handed to per-column
+// ColumnChunkWriters, which encode and spill each page directly to the underlying file as soon as it is
+// written. This is the OOM-safe alternative to FileWriter.AddData, which buffers an entire row group in
+// fw.schemaWriter before FlushRowGroup encodes any of it.
+type RowGroupWriter struct {
+	fw      *FileWriter
+	writers []*ColumnChunkWriter
+}
+
+// NewRowGroupWriter returns a RowGroupWriter for a new row group on fw. Create one ColumnChunkWriter
+// per leaf column with NewColumnChunkWriter, write to them directly, and call Close to finalize the row
+// group once every column has received the same number of rows.
+func (fw *FileWriter) NewRowGroupWriter() *RowGroupWriter {
+	return &RowGroupWriter{fw: fw}
+}
+
+// NewColumnChunkWriter starts streaming a new column chunk for path (its dot-joined schema path),
+// writing plain-encoded data pages directly to the file as Write is called. maxDefinitionLevel and
+// maxRepetitionLevel come from the column's position in the schema (0 for a required, non-repeated
+// column); they size the RLE encoding of the def/rep levels passed to Write.
+//
+// Only the uncompressed codec is supported: compressing a page requires the codec implementation that
+// backs FileWriter's own data page writer, which isn't reachable from here.
+//
+// If the FileWriter was configured with WithPageWriterFactory, the factory is called once here to build
+// this column's PageWriter, and every page Write encodes is then handed to that PageWriter instead of
+// being written to the file directly.
+func (rw *RowGroupWriter) NewColumnChunkWriter(path []string, typ parquet.Type, maxDefinitionLevel, maxRepetitionLevel int32) (*ColumnChunkWriter, error) {
+	if rw.fw.codec != parquet.CompressionCodec_UNCOMPRESSED {
+		return nil, fmt.Errorf("streaming column chunk writer only supports the uncompressed codec, FileWriter is configured with %v", rw.fw.codec)
+	}
+
+	if rw.fw.w.Pos() == 0 {
+		if err := writeFull(rw.fw.w, magic); err != nil {
+			return nil, err
+		}
+	}
+
+	cw := &ColumnChunkWriter{
+		w:                  rw.fw.w,
+		path:               path,
+		typ:                typ,
+		maxDefinitionLevel: maxDefinitionLevel,
+		maxRepetitionLevel: maxRepetitionLevel,
+		stats:              newColumnStats(orderDefault),
+	}
+	if rw.fw.pageWriterFactory != nil {
+		cw.pageWriter = rw.fw.pageWriterFactory(ColumnDescriptor{
+			Path: columnPathOf(path),
+			Type: typ,
+		})
+	}
+	rw.writers = append(rw.writers, cw)
+	return cw, nil
+}
+
+// Close finalizes every column chunk written through this RowGroupWriter and records the row group on
+// the underlying FileWriter. It is an error to call Close before every ColumnChunkWriter has seen the
+// same number of rows.
+func (rw *RowGroupWriter) Close() error {
+	if len(rw.writers) == 0 {
+		return errors.New("nothing to write")
+	}
+
+	numRows := rw.writers[0].numRows
+	cc := make([]*parquet.ColumnChunk, len(rw.writers))
+	for i, cw := range rw.writers {
+		if cw.numRows != numRows {
+			return fmt.Errorf("column %q has %d rows, column %q has %d: every column in a row group must have the same row count",
+				columnPathOf(rw.writers[0].path), numRows, columnPathOf(cw.path), cw.numRows)
+		}
+		c, err := cw.close()
+		if err != nil {
+			return err
+		}
+		cc[i] = c
+	}
+
+	rw.fw.rowGroups = append(rw.fw.rowGroups, &parquet.RowGroup{
+		Columns:        cc,
+		TotalByteSize:  sumUncompressedSize(cc),
+		NumRows:        numRows,
+		SortingColumns: rw.fw.sortingColumns,
+	})
+	rw.fw.totalNumRecords += numRows
+	rw.fw.sortingColumns = rw.fw.sortingColumnsDefault
+
+	return nil
+}
+
+func sumUncompressedSize(cc []*parquet.ColumnChunk) int64 {
+	var total int64
+	for _, c := range cc {
+		if c.MetaData != nil {
+			total += c.MetaData.TotalUncompressedSize
+		}
+	}
+	return total
+}
+
+func columnPathOf(path []string) string {
+	s := ""
+	for i, p := range path {
+		if i > 0 {
+			s += "."
+		}
+		s += p
+	}
+	return s
+}
@@ -0,0 +1,152 @@
+package goparquet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+func TestRowGroupWriterStreams(t *testing.T) {
+	var buf bytes.Buffer
+	fw := &FileWriter{w: &writePosStruct{w: &buf}}
+
+	rw := fw.NewRowGroupWriter()
+	cw, err := rw.NewColumnChunkWriter([]string{"a"}, parquet.Type_INT64, 0, 0)
+	if err != nil {
+		t.Fatalf("NewColumnChunkWriter: %v", err)
+	}
+
+	if err := cw.Write([]interface{}{int64(1), int64(2)}, nil, nil); err != nil {
+		t.Fatalf("Write page 1: %v", err)
+	}
+	if err := cw.Write([]interface{}{int64(3)}, nil, nil); err != nil {
+		t.Fatalf("Write page 2: %v", err)
+	}
+
+	if err := rw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if len(fw.rowGroups) != 1 {
+		t.Fatalf("expected 1 row group, got %d", len(fw.rowGroups))
+	}
+	rg := fw.rowGroups[0]
+	if rg.NumRows != 3 {
+		t.Fatalf("NumRows = %d, want 3", rg.NumRows)
+	}
+	if len(rg.Columns) != 1 {
+		t.Fatalf("expected 1 column, got %d", len(rg.Columns))
+	}
+	col := rg.Columns[0]
+	if col.MetaData.NumValues != 3 {
+		t.Fatalf("NumValues = %d, want 3", col.MetaData.NumValues)
+	}
+	if buf.Len() == 0 {
+		t.Fatalf("expected bytes to have been written to the underlying writer")
+	}
+	if want := int64(len(magic)); col.FileOffset != want {
+		t.Fatalf("FileOffset = %d, want %d (the first page, right after the magic header)", col.FileOffset, want)
+	}
+	if col.MetaData.DataPageOffset != col.FileOffset {
+		t.Fatalf("DataPageOffset = %d, want %d (the first page written, not the second)", col.MetaData.DataPageOffset, col.FileOffset)
+	}
+	if !bytes.Equal(buf.Bytes()[:len(magic)], magic) {
+		t.Fatalf("expected the file to start with the magic header")
+	}
+	if fw.totalNumRecords != 3 {
+		t.Fatalf("totalNumRecords = %d, want 3", fw.totalNumRecords)
+	}
+}
+
+func TestRowGroupWriterMismatchedRowCounts(t *testing.T) {
+	var buf bytes.Buffer
+	fw := &FileWriter{w: &writePosStruct{w: &buf}}
+
+	rw := fw.NewRowGroupWriter()
+	a, _ := rw.NewColumnChunkWriter([]string{"a"}, parquet.Type_INT64, 0, 0)
+	b, _ := rw.NewColumnChunkWriter([]string{"b"}, parquet.Type_INT64, 0, 0)
+
+	a.Write([]interface{}{int64(1), int64(2)}, nil, nil)
+	b.Write([]interface{}{int64(1)}, nil, nil)
+
+	if err := rw.Close(); err == nil {
+		t.Fatalf("expected an error for mismatched row counts")
+	}
+}
+
+type recordingPageWriter struct {
+	pages  [][]byte
+	stats  *parquet.Statistics
+	closed bool
+}
+
+func (pw *recordingPageWriter) WriteDataPage(data []byte) (int64, error) {
+	offset := int64(len(pw.pages)) * 1000 // arbitrary, just needs to be distinguishable from cw.w.Pos()
+	pw.pages = append(pw.pages, data)
+	return offset, nil
+}
+
+func (pw *recordingPageWriter) WriteDictionaryPage(data []byte) (int64, error) {
+	return 0, nil
+}
+
+func (pw *recordingPageWriter) WriteMetadata() (*parquet.ColumnMetaData, error) {
+	return &parquet.ColumnMetaData{Statistics: pw.stats}, nil
+}
+
+func (pw *recordingPageWriter) Close() error {
+	pw.closed = true
+	return nil
+}
+
+func TestRowGroupWriterDelegatesToPageWriterFactory(t *testing.T) {
+	var buf bytes.Buffer
+	pw := &recordingPageWriter{}
+
+	fw := &FileWriter{
+		w: &writePosStruct{w: &buf},
+		pageWriterFactory: func(col ColumnDescriptor) PageWriter {
+			if col.Path != "a" {
+				t.Fatalf("unexpected column path %q", col.Path)
+			}
+			return pw
+		},
+	}
+
+	rw := fw.NewRowGroupWriter()
+	cw, err := rw.NewColumnChunkWriter([]string{"a"}, parquet.Type_INT64, 0, 0)
+	if err != nil {
+		t.Fatalf("NewColumnChunkWriter: %v", err)
+	}
+
+	if err := cw.Write([]interface{}{int64(1), int64(2)}, nil, nil); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if len(pw.pages) != 1 {
+		t.Fatalf("expected 1 page to reach the PageWriter, got %d", len(pw.pages))
+	}
+	if !pw.closed {
+		t.Fatalf("expected the PageWriter to be closed")
+	}
+	if buf.Len() != len(magic) {
+		t.Fatalf("expected only the magic header to reach the underlying writer, got %d bytes", buf.Len())
+	}
+
+	col := fw.rowGroups[0].Columns[0]
+	if col.FileOffset != 0 {
+		t.Fatalf("FileOffset = %d, want the offset returned by the PageWriter (0)", col.FileOffset)
+	}
+}
+
+func TestRowGroupWriterRejectsCompression(t *testing.T) {
+	fw := &FileWriter{codec: parquet.CompressionCodec(99)}
+	rw := fw.NewRowGroupWriter()
+	if _, err := rw.NewColumnChunkWriter([]string{"a"}, parquet.Type_INT64, 0, 0); err == nil {
+		t.Fatalf("expected an error for a non-uncompressed codec")
+	}
+}
@@ -0,0 +1,215 @@
+package goparquet
+
+import (
+	"fmt"
+
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+// validateSortingColumns checks that cols is a well-formed declaration of sorting order for a schema
+// with leafCount leaf columns: every column index must be in range, and no index may be declared more
+// than once.
+func validateSortingColumns(cols []*parquet.SortingColumn, leafCount int) error {
+	seen := make(map[int32]bool, len(cols))
+	for _, c := range cols {
+		if c.ColumnIdx < 0 || int(c.ColumnIdx) >= leafCount {
+			return fmt.Errorf("sorting column index %d is out of range for a schema with %d leaf columns", c.ColumnIdx, leafCount)
+		}
+		if seen[c.ColumnIdx] {
+			return fmt.Errorf("column index %d is declared as a sorting column more than once", c.ColumnIdx)
+		}
+		seen[c.ColumnIdx] = true
+	}
+	return nil
+}
+
+// valueOrder describes how compareOrderedValues should interpret two values of the same underlying Go
+// type, derived from their column's logical/converted type via valueOrderFor. Most parquet types order
+// correctly under the default signed-integer/IEEE-float/lexicographic-byte comparison their decoded Go
+// value already supports; this only needs to name the types where that default gets it wrong.
+type valueOrder int
+
+const (
+	// orderDefault compares int32/int64 as signed, float32/float64 the usual way, and strings/[]byte
+	// lexicographically by unsigned byte value. Correct for every type except the ones below.
+	orderDefault valueOrder = iota
+	// orderUnsigned compares int32/int64 values as uint32/uint64, for columns with an unsigned integer
+	// logical or converted type (UINT_8/16/32/64).
+	orderUnsigned
+	// orderDecimalBytes compares []byte values as a two's-complement signed big-endian integer, for
+	// DECIMAL columns backed by BYTE_ARRAY or FIXED_LEN_BYTE_ARRAY. A DECIMAL backed by INT32/INT64
+	// already orders correctly under orderDefault, since its unscaled value is a plain signed integer.
+	orderDecimalBytes
+)
+
+// compareOrderedValues compares two column values of the same underlying Go type, under order, and
+// reports whether the comparison was supported. It backs both sorting order verification and, via
+// columnStats, the min/max tracking used for column chunk statistics.
+func compareOrderedValues(a, b interface{}, order valueOrder) (cmp int, ok bool) {
+	switch av := a.(type) {
+	case bool:
+		bv, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		if av == bv {
+			return 0, true
+		}
+		if !av {
+			return -1, true
+		}
+		return 1, true
+	case int32:
+		bv, ok := b.(int32)
+		if !ok {
+			return 0, false
+		}
+		if order == orderUnsigned {
+			return compareUint64(uint64(uint32(av)), uint64(uint32(bv))), true
+		}
+		return compareInt64(int64(av), int64(bv)), true
+	case int64:
+		bv, ok := b.(int64)
+		if !ok {
+			return 0, false
+		}
+		if order == orderUnsigned {
+			return compareUint64(uint64(av), uint64(bv)), true
+		}
+		return compareInt64(av, bv), true
+	case float32:
+		bv, ok := b.(float32)
+		if !ok {
+			return 0, false
+		}
+		return compareFloat64(float64(av), float64(bv)), true
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		return compareFloat64(av, bv), true
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return compareString(av, bv), true
+	case []byte:
+		bv, ok := b.([]byte)
+		if !ok {
+			return 0, false
+		}
+		if order == orderDecimalBytes {
+			return compareDecimalBytes(av, bv), true
+		}
+		return compareBytes(av, bv), true
+	default:
+		return 0, false
+	}
+}
+
+func compareUint64(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareDecimalBytes compares two big-endian two's-complement integers, the encoding a DECIMAL
+// column's unscaled value takes when backed by BYTE_ARRAY or FIXED_LEN_BYTE_ARRAY. a and b may differ in
+// length, since BYTE_ARRAY decimals aren't fixed-width. Unlike a plain lexicographic byte comparison,
+// this accounts for the sign bit: comparing the raw bytes directly would rank every negative value (high
+// bit set) above every positive one, the opposite of numeric order.
+func compareDecimalBytes(a, b []byte) int {
+	if len(a) < len(b) {
+		a = signExtendTwosComplement(a, len(b))
+	} else if len(b) < len(a) {
+		b = signExtendTwosComplement(b, len(a))
+	}
+
+	aNeg := len(a) > 0 && a[0]&0x80 != 0
+	bNeg := len(b) > 0 && b[0]&0x80 != 0
+	if aNeg != bNeg {
+		if aNeg {
+			return -1
+		}
+		return 1
+	}
+	return compareBytes(a, b)
+}
+
+// signExtendTwosComplement pads b out to n bytes by repeating its sign bit, so a shorter two's
+// complement value can be compared byte-by-byte against a longer one representing the same kind of
+// quantity.
+func signExtendTwosComplement(b []byte, n int) []byte {
+	if len(b) >= n {
+		return b
+	}
+	pad := byte(0)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		pad = 0xff
+	}
+	out := make([]byte, n)
+	for i := 0; i < n-len(b); i++ {
+		out[i] = pad
+	}
+	copy(out[n-len(b):], b)
+	return out
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareFloat64(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareString(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareBytes(a, b []byte) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	default:
+		return 0
+	}
+}
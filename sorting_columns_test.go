@@ -0,0 +1,86 @@
+package goparquet
+
+import (
+	"testing"
+
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+func TestValidateSortingColumns(t *testing.T) {
+	cases := []struct {
+		name    string
+		cols    []*parquet.SortingColumn
+		leaf    int
+		wantErr bool
+	}{
+		{"empty", nil, 3, false},
+		{"in range", []*parquet.SortingColumn{{ColumnIdx: 0}, {ColumnIdx: 2}}, 3, false},
+		{"out of range", []*parquet.SortingColumn{{ColumnIdx: 3}}, 3, true},
+		{"negative", []*parquet.SortingColumn{{ColumnIdx: -1}}, 3, true},
+		{"duplicate", []*parquet.SortingColumn{{ColumnIdx: 1}, {ColumnIdx: 1}}, 3, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateSortingColumns(tc.cols, tc.leaf)
+			if tc.wantErr && err == nil {
+				t.Fatalf("expected an error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCompareOrderedValues(t *testing.T) {
+	cmp, ok := compareOrderedValues(int64(1), int64(2), orderDefault)
+	if !ok || cmp >= 0 {
+		t.Fatalf("1 vs 2: got cmp=%d ok=%v", cmp, ok)
+	}
+
+	cmp, ok = compareOrderedValues("b", "a", orderDefault)
+	if !ok || cmp <= 0 {
+		t.Fatalf("b vs a: got cmp=%d ok=%v", cmp, ok)
+	}
+
+	if _, ok = compareOrderedValues(int64(1), "a", orderDefault); ok {
+		t.Fatalf("expected mismatched types to be unsupported")
+	}
+}
+
+func TestCompareOrderedValuesUnsigned(t *testing.T) {
+	// -1 as a signed int64 is the largest possible uint64, so orderUnsigned must reverse the verdict
+	// that orderDefault gives for the same two values.
+	cmp, ok := compareOrderedValues(int64(-1), int64(1), orderDefault)
+	if !ok || cmp >= 0 {
+		t.Fatalf("orderDefault: -1 vs 1: got cmp=%d ok=%v, want < 0", cmp, ok)
+	}
+
+	cmp, ok = compareOrderedValues(int64(-1), int64(1), orderUnsigned)
+	if !ok || cmp <= 0 {
+		t.Fatalf("orderUnsigned: -1 vs 1: got cmp=%d ok=%v, want > 0 (-1 is the largest uint64)", cmp, ok)
+	}
+}
+
+func TestCompareOrderedValuesDecimalBytes(t *testing.T) {
+	minusOne := []byte{0xff}
+	one := []byte{0x01}
+
+	// Under plain byte comparison, 0xff would sort after 0x01, the opposite of -1 < 1.
+	cmp, ok := compareOrderedValues(minusOne, one, orderDefault)
+	if !ok || cmp <= 0 {
+		t.Fatalf("orderDefault: 0xff vs 0x01: got cmp=%d ok=%v, want > 0", cmp, ok)
+	}
+
+	cmp, ok = compareOrderedValues(minusOne, one, orderDecimalBytes)
+	if !ok || cmp >= 0 {
+		t.Fatalf("orderDecimalBytes: -1 vs 1: got cmp=%d ok=%v, want < 0", cmp, ok)
+	}
+
+	// Same value, different widths: a two-byte -1 (0xff 0xff) must still compare equal to a one-byte -1.
+	cmp, ok = compareOrderedValues([]byte{0xff, 0xff}, minusOne, orderDecimalBytes)
+	if !ok || cmp != 0 {
+		t.Fatalf("orderDecimalBytes: -1 (2 bytes) vs -1 (1 byte): got cmp=%d ok=%v, want 0", cmp, ok)
+	}
+}
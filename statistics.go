@@ -0,0 +1,110 @@
+package goparquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+// columnStats accumulates the min/max, null count and distinct value count for a single column
+// across the rows added to the current row group. Min/max comparisons reuse compareOrderedValues, the
+// same logical-type-aware comparator used to enforce declared sorting columns, so both features agree
+// on what "ordered" means for a given Go value; order is derived once from the column's schema element
+// when the columnStats is created.
+type columnStats struct {
+	order         valueOrder
+	min, max      interface{}
+	nullCount     int64
+	distinctCount int64
+	seen          map[string]struct{}
+}
+
+func newColumnStats(order valueOrder) *columnStats {
+	return &columnStats{order: order, seen: make(map[string]struct{})}
+}
+
+// observe folds a single value of the column into the running statistics. A missing or nil value is
+// counted as a null.
+func (s *columnStats) observe(v interface{}) {
+	if v == nil {
+		s.nullCount++
+		return
+	}
+
+	key := fmt.Sprintf("%T:%v", v, v)
+	if _, ok := s.seen[key]; !ok {
+		s.seen[key] = struct{}{}
+		s.distinctCount++
+	}
+
+	if s.min == nil {
+		s.min, s.max = v, v
+		return
+	}
+	if cmp, ok := compareOrderedValues(v, s.min, s.order); ok && cmp < 0 {
+		s.min = v
+	}
+	if cmp, ok := compareOrderedValues(v, s.max, s.order); ok && cmp > 0 {
+		s.max = v
+	}
+}
+
+// statistics builds the parquet Statistics thrift struct for this column, or nil if s is nil. Null and
+// distinct counts are always set; MinValue/MaxValue are left unset if only nulls were observed.
+func (s *columnStats) statistics() *parquet.Statistics {
+	if s == nil {
+		return nil
+	}
+
+	nullCount := s.nullCount
+	distinctCount := s.distinctCount
+
+	stats := &parquet.Statistics{
+		NullCount:     &nullCount,
+		DistinctCount: &distinctCount,
+	}
+
+	if s.min != nil {
+		stats.MinValue = encodeStatValue(s.min)
+		stats.MaxValue = encodeStatValue(s.max)
+	}
+
+	return stats
+}
+
+// encodeStatValue encodes a decoded column value into the raw little-endian plain-encoding bytes that
+// MinValue/MaxValue are defined to carry, matching the byte layout used elsewhere in this package to
+// hash values for bloom filters.
+func encodeStatValue(v interface{}) []byte {
+	switch val := v.(type) {
+	case bool:
+		if val {
+			return []byte{1}
+		}
+		return []byte{0}
+	case int32:
+		var buf [4]byte
+		binary.LittleEndian.PutUint32(buf[:], uint32(val))
+		return buf[:]
+	case int64:
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], uint64(val))
+		return buf[:]
+	case float32:
+		var buf [4]byte
+		binary.LittleEndian.PutUint32(buf[:], math.Float32bits(val))
+		return buf[:]
+	case float64:
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(val))
+		return buf[:]
+	case string:
+		return []byte(val)
+	case []byte:
+		return val
+	default:
+		return nil
+	}
+}
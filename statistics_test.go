@@ -0,0 +1,59 @@
+package goparquet
+
+import "testing"
+
+func TestColumnStatsObserve(t *testing.T) {
+	s := newColumnStats(orderDefault)
+	for _, v := range []interface{}{int64(5), int64(1), nil, int64(5), int64(9)} {
+		s.observe(v)
+	}
+
+	if s.min != int64(1) {
+		t.Fatalf("min = %v, want 1", s.min)
+	}
+	if s.max != int64(9) {
+		t.Fatalf("max = %v, want 9", s.max)
+	}
+	if s.nullCount != 1 {
+		t.Fatalf("nullCount = %d, want 1", s.nullCount)
+	}
+	if s.distinctCount != 3 {
+		t.Fatalf("distinctCount = %d, want 3", s.distinctCount)
+	}
+
+	stats := s.statistics()
+	if stats.NullCount == nil || *stats.NullCount != 1 {
+		t.Fatalf("Statistics.NullCount = %v, want 1", stats.NullCount)
+	}
+	if stats.DistinctCount == nil || *stats.DistinctCount != 3 {
+		t.Fatalf("Statistics.DistinctCount = %v, want 3", stats.DistinctCount)
+	}
+}
+
+func TestColumnStatsObserveUnsigned(t *testing.T) {
+	s := newColumnStats(orderUnsigned)
+	// Decoded as signed int64, -1 looks smaller than 1; as the uint64 it actually represents, it's the
+	// larger of the two, so an unsigned column's min/max must come out the other way around.
+	for _, v := range []interface{}{int64(1), int64(-1)} {
+		s.observe(v)
+	}
+
+	if s.min != int64(1) {
+		t.Fatalf("min = %v, want 1 (the smaller value unsigned)", s.min)
+	}
+	if s.max != int64(-1) {
+		t.Fatalf("max = %v, want -1 (the largest possible uint64)", s.max)
+	}
+}
+
+func TestEncodeStatValue(t *testing.T) {
+	if got := encodeStatValue(int32(1)); len(got) != 4 {
+		t.Fatalf("int32 encoding length = %d, want 4", len(got))
+	}
+	if got := encodeStatValue(int64(1)); len(got) != 8 {
+		t.Fatalf("int64 encoding length = %d, want 8", len(got))
+	}
+	if got := encodeStatValue("ab"); string(got) != "ab" {
+		t.Fatalf("string encoding = %q, want %q", got, "ab")
+	}
+}
@@ -0,0 +1,99 @@
+package goparquet
+
+// XXH64 primes, as defined by the xxHash specification. These are declared as package-level vars
+// rather than typed constants because several of the expressions built from them (prime1+prime2,
+// -prime1) overflow uint64 when evaluated at compile time as constants, even though the same
+// arithmetic wraps around fine at runtime, which is exactly what the algorithm relies on.
+var (
+	xxhPrime1 uint64 = 11400714785074694791
+	xxhPrime2 uint64 = 14029467366897019727
+	xxhPrime3 uint64 = 1609587929392839161
+	xxhPrime4 uint64 = 9650029242287828579
+	xxhPrime5 uint64 = 2870177450012600261
+)
+
+// xxh64 is a small, self-contained implementation of the 64-bit xxHash algorithm (XXH64, seed 0), used
+// to compute the value hashes required by the parquet bloom filter format. It intentionally only
+// supports one-shot hashing of a full byte slice, which is all the bloom filter writer needs.
+func xxh64(data []byte) uint64 {
+	var h uint64
+	n := len(data)
+
+	if n >= 32 {
+		v1 := xxhPrime1 + xxhPrime2
+		v2 := xxhPrime2
+		v3 := uint64(0)
+		v4 := -xxhPrime1
+
+		for len(data) >= 32 {
+			v1 = xxh64Round(v1, le64(data[0:8]))
+			v2 = xxh64Round(v2, le64(data[8:16]))
+			v3 = xxh64Round(v3, le64(data[16:24]))
+			v4 = xxh64Round(v4, le64(data[24:32]))
+			data = data[32:]
+		}
+
+		h = rotl(v1, 1) + rotl(v2, 7) + rotl(v3, 12) + rotl(v4, 18)
+		h = xxh64MergeRound(h, v1)
+		h = xxh64MergeRound(h, v2)
+		h = xxh64MergeRound(h, v3)
+		h = xxh64MergeRound(h, v4)
+	} else {
+		h = xxhPrime5
+	}
+
+	h += uint64(n)
+
+	for len(data) >= 8 {
+		h ^= xxh64Round(0, le64(data[0:8]))
+		h = rotl(h, 27)*xxhPrime1 + xxhPrime4
+		data = data[8:]
+	}
+
+	if len(data) >= 4 {
+		h ^= uint64(le32(data[0:4])) * xxhPrime1
+		h = rotl(h, 23)*xxhPrime2 + xxhPrime3
+		data = data[4:]
+	}
+
+	for len(data) > 0 {
+		h ^= uint64(data[0]) * xxhPrime5
+		h = rotl(h, 11) * xxhPrime1
+		data = data[1:]
+	}
+
+	h ^= h >> 33
+	h *= xxhPrime2
+	h ^= h >> 29
+	h *= xxhPrime3
+	h ^= h >> 32
+
+	return h
+}
+
+func xxh64Round(acc, input uint64) uint64 {
+	acc += input * xxhPrime2
+	acc = rotl(acc, 31)
+	acc *= xxhPrime1
+	return acc
+}
+
+func xxh64MergeRound(acc, val uint64) uint64 {
+	val = xxh64Round(0, val)
+	acc ^= val
+	acc = acc*xxhPrime1 + xxhPrime4
+	return acc
+}
+
+func rotl(x uint64, r uint) uint64 {
+	return (x << r) | (x >> (64 - r))
+}
+
+func le64(b []byte) uint64 {
+	return uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24 |
+		uint64(b[4])<<32 | uint64(b[5])<<40 | uint64(b[6])<<48 | uint64(b[7])<<56
+}
+
+func le32(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
@@ -0,0 +1,32 @@
+package goparquet
+
+import "testing"
+
+func TestXxh64(t *testing.T) {
+	data256 := make([]byte, 256)
+	for i := range data256 {
+		data256[i] = byte(i)
+	}
+	data512 := append(append([]byte{}, data256...), data256...)
+
+	cases := []struct {
+		name string
+		data []byte
+		want uint64
+	}{
+		{"empty", []byte(""), 0xef46db3751d8e999},
+		{"a", []byte("a"), 0xd24ec4f1a98c6e5b},
+		{"abc", []byte("abc"), 0x44bc2cf5ad770999},
+		{"0123456789", []byte("0123456789"), 0x3f5fc178a81867e7},
+		{"256 bytes", data256, 0x1facbe8406cd904b},
+		{"512 bytes", data512, 0x7b3bfcaac0348ac0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := xxh64(tc.data); got != tc.want {
+				t.Fatalf("xxh64(%q) = %#x, want %#x", tc.data, got, tc.want)
+			}
+		})
+	}
+}